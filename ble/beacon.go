@@ -0,0 +1,170 @@
+package ble
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// iBeacon company identifier for Apple, as it appears as a key in
+// ManufacturerData.
+const iBeaconCompanyID = "004c"
+
+// Eddystone service UUID, as it appears as a key in ServiceData.
+const eddystoneServiceUUID = "feaa"
+
+const (
+	eddystoneFrameUID = 0x00
+	eddystoneFrameURL = 0x10
+	eddystoneFrameTLM = 0x20
+)
+
+var eddystoneURLSchemes = []string{
+	"http://www.",
+	"https://www.",
+	"http://",
+	"https://",
+}
+
+var eddystoneURLExpansions = []string{
+	".com/", ".org/", ".edu/", ".net/", ".info/", ".biz/", ".gov/",
+	".com", ".org", ".edu", ".net", ".info", ".biz", ".gov",
+}
+
+// IBeacon is the payload of an Apple iBeacon advertisement.
+type IBeacon struct {
+	UUID          string
+	Major         uint16
+	Minor         uint16
+	MeasuredPower int8
+}
+
+// EddystoneUID is the payload of an Eddystone UID frame.
+type EddystoneUID struct {
+	TxPower   int8
+	Namespace []byte
+	Instance  []byte
+}
+
+// EddystoneURL is the payload of an Eddystone URL frame.
+type EddystoneURL struct {
+	TxPower int8
+	URL     string
+}
+
+// EddystoneTLM is the payload of an Eddystone telemetry (TLM) frame.
+type EddystoneTLM struct {
+	Version             uint8
+	BatteryMillivolts   uint16
+	TemperatureCelsius  float32
+	AdvertisingPDUCount uint32
+	SecondsSinceBoot    float32
+}
+
+// IBeacon decodes p's ManufacturerData as an Apple iBeacon frame. It returns
+// false if the peripheral is not advertising one.
+func (p *Peripheral) IBeacon() (IBeacon, bool) {
+	data := p.ManufacturerData()[iBeaconCompanyID]
+	if len(data) < 23 || data[0] != 0x02 || data[1] != 0x15 {
+		return IBeacon{}, false
+	}
+
+	return IBeacon{
+		UUID:          toUUID(data[2:18]),
+		Major:         binary.BigEndian.Uint16(data[18:20]),
+		Minor:         binary.BigEndian.Uint16(data[20:22]),
+		MeasuredPower: int8(data[22]),
+	}, true
+}
+
+// EddystoneUID decodes p's ServiceData as an Eddystone UID frame. It returns
+// false if the peripheral is not advertising one.
+func (p *Peripheral) EddystoneUID() (EddystoneUID, bool) {
+	data := p.ServiceData(eddystoneServiceUUID)
+	if len(data) < 18 || data[0] != eddystoneFrameUID {
+		return EddystoneUID{}, false
+	}
+
+	return EddystoneUID{
+		TxPower:   int8(data[1]),
+		Namespace: append([]byte{}, data[2:12]...),
+		Instance:  append([]byte{}, data[12:18]...),
+	}, true
+}
+
+// EddystoneURL decodes p's ServiceData as an Eddystone URL frame. It returns
+// false if the peripheral is not advertising one.
+func (p *Peripheral) EddystoneURL() (EddystoneURL, bool) {
+	data := p.ServiceData(eddystoneServiceUUID)
+	if len(data) < 3 || data[0] != eddystoneFrameURL {
+		return EddystoneURL{}, false
+	}
+
+	scheme := int(data[2])
+	if scheme >= len(eddystoneURLSchemes) {
+		return EddystoneURL{}, false
+	}
+
+	url := eddystoneURLSchemes[scheme]
+	for _, b := range data[3:] {
+		if int(b) < len(eddystoneURLExpansions) {
+			url += eddystoneURLExpansions[b]
+		} else {
+			url += string(rune(b))
+		}
+	}
+
+	return EddystoneURL{
+		TxPower: int8(data[1]),
+		URL:     url,
+	}, true
+}
+
+// EddystoneTLM decodes p's ServiceData as an Eddystone telemetry frame. It
+// returns false if the peripheral is not advertising one.
+func (p *Peripheral) EddystoneTLM() (EddystoneTLM, bool) {
+	data := p.ServiceData(eddystoneServiceUUID)
+	if len(data) < 14 || data[0] != eddystoneFrameTLM {
+		return EddystoneTLM{}, false
+	}
+
+	return EddystoneTLM{
+		Version:             data[1],
+		BatteryMillivolts:   binary.BigEndian.Uint16(data[2:4]),
+		TemperatureCelsius:  float32(int8(data[4])) + float32(data[5])/256,
+		AdvertisingPDUCount: binary.BigEndian.Uint32(data[6:10]),
+		SecondsSinceBoot:    float32(binary.BigEndian.Uint32(data[10:14])) / 10,
+	}, true
+}
+
+// ErrNoMeasuredPower is returned by Distance when the peripheral is not
+// advertising a recognized beacon frame to read a measured power from.
+var ErrNoMeasuredPower = errors.New("ble: peripheral has no measured power")
+
+// Distance estimates the distance in meters between the scanner and p using
+// the log-distance path loss model, given p's RSSI and a measured power
+// taken from an iBeacon or Eddystone UID frame. n is the path-loss exponent;
+// pass 2.0 for free-space propagation.
+func (p *Peripheral) Distance(n float64) (float64, error) {
+	measuredPower, ok := p.measuredPower()
+	if !ok {
+		return 0, ErrNoMeasuredPower
+	}
+
+	return logDistance(measuredPower, p.RSSI(), n), nil
+}
+
+// logDistance implements the log-distance path loss model.
+func logDistance(measuredPower int8, rssi int, n float64) float64 {
+	return math.Pow(10, (float64(measuredPower)-float64(rssi))/(10*n))
+}
+
+func (p *Peripheral) measuredPower() (int8, bool) {
+	if beacon, ok := p.IBeacon(); ok {
+		return beacon.MeasuredPower, true
+	}
+	if uid, ok := p.EddystoneUID(); ok {
+		return uid.TxPower, true
+	}
+	return 0, false
+}
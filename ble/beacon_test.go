@@ -0,0 +1,133 @@
+package ble
+
+import "testing"
+
+func TestIBeacon(t *testing.T) {
+	data := []byte{
+		0x02, 0x15,
+		0xe2, 0xc5, 0x6d, 0xb5, 0xdf, 0xfb, 0x48, 0xd2, 0xb0, 0x60, 0xd0, 0xf5, 0xa7, 0x10, 0x96, 0xe0,
+		0x00, 0x01,
+		0x00, 0x02,
+		0xc5,
+	}
+	p := &Peripheral{manufacturerData: map[string][]byte{iBeaconCompanyID: data}}
+
+	got, ok := p.IBeacon()
+	if !ok {
+		t.Fatal("IBeacon() returned false, want true")
+	}
+
+	want := IBeacon{
+		UUID:          "e2c56db5-dffb-48d2-b060-d0f5a71096e0",
+		Major:         1,
+		Minor:         2,
+		MeasuredPower: -59,
+	}
+	if got != want {
+		t.Errorf("IBeacon() = %+v, want %+v", got, want)
+	}
+}
+
+func TestIBeaconWrongPrefix(t *testing.T) {
+	p := &Peripheral{manufacturerData: map[string][]byte{iBeaconCompanyID: {0x01, 0x15, 0x00}}}
+	if _, ok := p.IBeacon(); ok {
+		t.Error("IBeacon() = true for non-iBeacon frame, want false")
+	}
+}
+
+func TestEddystoneUID(t *testing.T) {
+	data := append([]byte{eddystoneFrameUID, 0xc5},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}..., // namespace, 10 bytes
+	)
+	data = append(data, []byte{10, 11, 12, 13, 14, 15}...) // instance, 6 bytes
+
+	p := &Peripheral{servicesData: map[string][]byte{eddystoneServiceUUID: data}}
+
+	got, ok := p.EddystoneUID()
+	if !ok {
+		t.Fatal("EddystoneUID() returned false, want true")
+	}
+	if got.TxPower != -59 {
+		t.Errorf("TxPower = %d, want -59", got.TxPower)
+	}
+	if len(got.Namespace) != 10 || len(got.Instance) != 6 {
+		t.Errorf("Namespace/Instance lengths = %d/%d, want 10/6", len(got.Namespace), len(got.Instance))
+	}
+}
+
+func TestEddystoneUIDTruncatedDoesNotPanic(t *testing.T) {
+	// 17 bytes: one short of the 18 a UID frame requires.
+	data := make([]byte, 17)
+	data[0] = eddystoneFrameUID
+
+	p := &Peripheral{servicesData: map[string][]byte{eddystoneServiceUUID: data}}
+
+	if _, ok := p.EddystoneUID(); ok {
+		t.Error("EddystoneUID() = true for truncated frame, want false")
+	}
+}
+
+func TestEddystoneURL(t *testing.T) {
+	// scheme 0 ("http://www.") + "example" + expansion 0x00 (".com/")
+	data := append([]byte{eddystoneFrameURL, 0xc5, 0x00}, []byte("example")...)
+	data = append(data, 0x00)
+
+	p := &Peripheral{servicesData: map[string][]byte{eddystoneServiceUUID: data}}
+
+	got, ok := p.EddystoneURL()
+	if !ok {
+		t.Fatal("EddystoneURL() returned false, want true")
+	}
+	if want := "http://www.example.com/"; got.URL != want {
+		t.Errorf("URL = %q, want %q", got.URL, want)
+	}
+}
+
+func TestEddystoneTLM(t *testing.T) {
+	data := []byte{
+		eddystoneFrameTLM, 0x00,
+		0x0c, 0xe4, // battery: 3300 mV
+		0x17, 0x80, // temperature: 23.5 C
+		0x00, 0x00, 0x00, 0x0a, // advertising PDU count: 10
+		0x00, 0x00, 0x00, 0x64, // seconds since boot: 10.0s
+	}
+
+	p := &Peripheral{servicesData: map[string][]byte{eddystoneServiceUUID: data}}
+
+	got, ok := p.EddystoneTLM()
+	if !ok {
+		t.Fatal("EddystoneTLM() returned false, want true")
+	}
+	if got.BatteryMillivolts != 3300 {
+		t.Errorf("BatteryMillivolts = %d, want 3300", got.BatteryMillivolts)
+	}
+	if got.TemperatureCelsius != 23.5 {
+		t.Errorf("TemperatureCelsius = %v, want 23.5", got.TemperatureCelsius)
+	}
+	if got.AdvertisingPDUCount != 10 {
+		t.Errorf("AdvertisingPDUCount = %d, want 10", got.AdvertisingPDUCount)
+	}
+	if got.SecondsSinceBoot != 10.0 {
+		t.Errorf("SecondsSinceBoot = %v, want 10.0", got.SecondsSinceBoot)
+	}
+}
+
+func TestLogDistance(t *testing.T) {
+	// RSSI equal to measured power means the receiver is at the reference
+	// distance of 1 meter.
+	if got := logDistance(-59, -59, 2.0); got != 1.0 {
+		t.Errorf("logDistance(-59, -59, 2.0) = %v, want 1.0", got)
+	}
+
+	// Weaker RSSI than measured power means the receiver is farther away.
+	if got := logDistance(-59, -79, 2.0); got <= 1.0 {
+		t.Errorf("logDistance(-59, -79, 2.0) = %v, want > 1.0", got)
+	}
+}
+
+func TestDistanceNoMeasuredPower(t *testing.T) {
+	p := &Peripheral{}
+	if _, err := p.Distance(2.0); err != ErrNoMeasuredPower {
+		t.Errorf("Distance() error = %v, want ErrNoMeasuredPower", err)
+	}
+}
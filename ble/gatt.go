@@ -0,0 +1,233 @@
+package ble
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Connect opens a GATT connection to the peripheral. It blocks until the
+// underlying cordova-plugin-ble-central connect callback fires or ctx is
+// cancelled, whichever happens first.
+func (p *Peripheral) Connect(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	success := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+	failure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- fmt.Errorf("ble: connect %s: %s", p.ID(), jsErrorString(args)):
+		default:
+		}
+		return nil
+	})
+
+	js.Global.Get("ble").Call("connect", p.ID(), success, failure)
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Disconnect tears down the GATT connection previously opened with Connect.
+func (p *Peripheral) Disconnect() error {
+	done := make(chan error, 1)
+
+	success := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+	failure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- fmt.Errorf("ble: disconnect %s: %s", p.ID(), jsErrorString(args)):
+		default:
+		}
+		return nil
+	})
+
+	js.Global.Get("ble").Call("disconnect", p.ID(), success, failure)
+
+	return <-done
+}
+
+// Read fetches the current value of characteristic on service.
+func (p *Peripheral) Read(service, characteristic string) ([]byte, error) {
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+
+	success := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		var data []byte
+		if len(args) > 0 {
+			data = js.Global.Get("Uint8Array").New(args[0]).Interface().([]byte)
+		}
+		select {
+		case done <- result{data: data}:
+		default:
+		}
+		return nil
+	})
+	failure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- result{err: fmt.Errorf("ble: read %s/%s: %s", service, characteristic, jsErrorString(args))}:
+		default:
+		}
+		return nil
+	})
+
+	js.Global.Get("ble").Call("read", p.ID(), service, characteristic, success, failure)
+
+	r := <-done
+	return r.data, r.err
+}
+
+// Write sends data to characteristic on service. When withResponse is true,
+// the write is acknowledged by the peripheral (ble.write); otherwise it is
+// fire-and-forget (ble.writeWithoutResponse).
+func (p *Peripheral) Write(service, characteristic string, data []byte, withResponse bool) error {
+	done := make(chan error, 1)
+
+	success := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- nil:
+		default:
+		}
+		return nil
+	})
+	failure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		select {
+		case done <- fmt.Errorf("ble: write %s/%s: %s", service, characteristic, jsErrorString(args)):
+		default:
+		}
+		return nil
+	})
+
+	buffer := toArrayBuffer(data)
+
+	call := "write"
+	if !withResponse {
+		call = "writeWithoutResponse"
+	}
+	js.Global.Get("ble").Call(call, p.ID(), service, characteristic, buffer, success, failure)
+
+	return <-done
+}
+
+// notifyBuffer bounds the number of notifications Subscribe queues between
+// the JS callback and its forwarding goroutine before it starts dropping
+// them, so a stalled consumer can't pile up unbounded goroutines.
+const notifyBuffer = 16
+
+// Subscribe starts notifications for characteristic on service and returns a
+// channel delivering each notified value along with a cancel function. A
+// single goroutine forwards notifications from the JS callback to the
+// returned channel; it exits, and stopNotification is issued to the JS layer
+// exactly once, when either ctx is cancelled or the cancel function is
+// called (it is safe to call more than once). If the consumer falls behind
+// and notifyBuffer notifications are already queued, newly arriving ones
+// are dropped rather than leaking memory or goroutines.
+func (p *Peripheral) Subscribe(ctx context.Context, service, characteristic string) (<-chan []byte, func() error) {
+	raw := make(chan []byte, notifyBuffer)
+	values := make(chan []byte)
+	stop := make(chan struct{})
+
+	success := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		if len(args) == 0 {
+			return nil
+		}
+		data := js.Global.Get("Uint8Array").New(args[0]).Interface().([]byte)
+		select {
+		case raw <- data:
+		default:
+		}
+		return nil
+	})
+	failure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+		return nil
+	})
+
+	js.Global.Get("ble").Call("startNotification", p.ID(), service, characteristic, success, failure)
+
+	var once sync.Once
+	var stopErr error
+	doStop := func() error {
+		once.Do(func() {
+			close(stop)
+
+			done := make(chan error, 1)
+
+			stopSuccess := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+				select {
+				case done <- nil:
+				default:
+				}
+				return nil
+			})
+			stopFailure := js.MakeFunc(func(this *js.Object, args []*js.Object) interface{} {
+				select {
+				case done <- fmt.Errorf("ble: stopNotification %s/%s: %s", service, characteristic, jsErrorString(args)):
+				default:
+				}
+				return nil
+			})
+
+			js.Global.Get("ble").Call("stopNotification", p.ID(), service, characteristic, stopSuccess, stopFailure)
+			stopErr = <-done
+		})
+		return stopErr
+	}
+
+	go func() {
+		defer close(values)
+		for {
+			select {
+			case data := <-raw:
+				select {
+				case values <- data:
+				case <-stop:
+					return
+				case <-ctx.Done():
+					doStop()
+					return
+				}
+			case <-stop:
+				return
+			case <-ctx.Done():
+				doStop()
+				return
+			}
+		}
+	}()
+
+	return values, doStop
+}
+
+// toArrayBuffer copies data into a new ArrayBuffer, the type the ble
+// plugin's write calls expect.
+func toArrayBuffer(data []byte) *js.Object {
+	return js.Global.Get("Uint8Array").New(data).Get("buffer")
+}
+
+// jsErrorString renders the first argument passed to a ble plugin failure
+// callback, which is usually a string but occasionally an Error-like object.
+func jsErrorString(args []*js.Object) string {
+	if len(args) == 0 || args[0] == nil || args[0] == js.Undefined {
+		return "unknown error"
+	}
+	return args[0].String()
+}